@@ -0,0 +1,231 @@
+package logz
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what an async writer does when its buffer is full.
+type OverflowPolicy byte
+
+const (
+	// DropOldest evicts the oldest queued record to make room.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the record that triggered the overflow.
+	DropNewest
+	// BlockCaller blocks the logging call until space is available.
+	BlockCaller
+	// FallbackSync writes the record synchronously, bypassing the queue.
+	FallbackSync
+)
+
+// AsyncOptions configures InitAsync.
+type AsyncOptions struct {
+	// BufferSize is the number of records the queue holds before Overflow
+	// kicks in. Defaults to 1024.
+	BufferSize int
+	// FlushInterval is how often queued records are written out in the
+	// background. Defaults to 5s.
+	FlushInterval time.Duration
+	Overflow      OverflowPolicy
+}
+
+// Flusher is implemented by writers that buffer records and can be told to
+// push them out on demand, such as the writer InitAsync installs.
+type Flusher interface {
+	Flush() error
+}
+
+// Stats reports the state of an async writer's queue.
+type Stats struct {
+	Queued  int64
+	Dropped int64
+	Flushed int64
+}
+
+type asyncWriter struct {
+	out      io.Writer
+	queue    chan []byte
+	overflow OverflowPolicy
+	interval time.Duration
+	done     chan struct{}
+	// flushReq asks run() to drain pending plus whatever's left on queue
+	// and write it all out, replying on the channel it's handed once
+	// that's done. Flush must go through run() rather than reading queue
+	// itself, otherwise the two goroutines race over the same records:
+	// run() can pull a record into its local pending slice where Flush
+	// can no longer see it, leaving it unwritten until the next tick.
+	flushReq chan chan struct{}
+	wg       sync.WaitGroup
+
+	queued  int64
+	dropped int64
+	flushed int64
+}
+
+func newAsyncWriter(out io.Writer, opts AsyncOptions) *asyncWriter {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	w := &asyncWriter{
+		out:      out,
+		queue:    make(chan []byte, opts.BufferSize),
+		overflow: opts.Overflow,
+		interval: opts.FlushInterval,
+		done:     make(chan struct{}),
+		flushReq: make(chan chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var pending [][]byte
+	flush := func() {
+		for _, b := range pending {
+			w.out.Write(b)
+			atomic.AddInt64(&w.flushed, 1)
+		}
+		pending = pending[:0]
+	}
+	drainQueue := func() {
+		for {
+			select {
+			case b, ok := <-w.queue:
+				if !ok {
+					return
+				}
+				pending = append(pending, b)
+				atomic.AddInt64(&w.queued, -1)
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case b, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, b)
+			atomic.AddInt64(&w.queued, -1)
+		case <-ticker.C:
+			flush()
+		case reply := <-w.flushReq:
+			drainQueue()
+			flush()
+			close(reply)
+		}
+	}
+}
+
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	b := append([]byte(nil), p...)
+	select {
+	case w.queue <- b:
+		atomic.AddInt64(&w.queued, 1)
+		return len(p), nil
+	default:
+	}
+
+	switch w.overflow {
+	case DropNewest:
+		atomic.AddInt64(&w.dropped, 1)
+	case DropOldest:
+		select {
+		case <-w.queue:
+			atomic.AddInt64(&w.queued, -1)
+			atomic.AddInt64(&w.dropped, 1)
+		default:
+		}
+		select {
+		case w.queue <- b:
+			atomic.AddInt64(&w.queued, 1)
+		default:
+			atomic.AddInt64(&w.dropped, 1)
+		}
+	case BlockCaller:
+		w.queue <- b
+		atomic.AddInt64(&w.queued, 1)
+	case FallbackSync:
+		return w.out.Write(p)
+	}
+	return len(p), nil
+}
+
+// Flush synchronously writes out every record currently queued, including
+// any run() has already pulled off the channel into its own pending
+// buffer, by asking run() itself to drain and flush rather than reading
+// w.queue independently (see the flushReq field doc).
+func (w *asyncWriter) Flush() error {
+	reply := make(chan struct{})
+	select {
+	case w.flushReq <- reply:
+	case <-w.done:
+		return nil
+	}
+	select {
+	case <-reply:
+	case <-w.done:
+	}
+	return nil
+}
+
+func (w *asyncWriter) Close() error {
+	close(w.queue)
+	w.wg.Wait()
+	close(w.done)
+	if c, ok := w.out.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (w *asyncWriter) Stats() Stats {
+	return Stats{
+		Queued:  atomic.LoadInt64(&w.queued),
+		Dropped: atomic.LoadInt64(&w.dropped),
+		Flushed: atomic.LoadInt64(&w.flushed),
+	}
+}
+
+// InitAsync is like Init, but formatted records are enqueued onto a bounded
+// buffer and written out by a background goroutine, flushing every
+// opts.FlushInterval. Use Flush or Close to drain it explicitly.
+func InitAsync(out io.Writer, logStdLvl, logOutLvl, stackLevel LogLevel, logFileName bool, opts AsyncOptions) error {
+	return InitWithFormatter(newAsyncWriter(out, opts), logStdLvl, logOutLvl, stackLevel, logFileName, &TextFormatter{})
+}
+
+// Flush drains any buffered records to their destination. It is a no-op
+// when logz wasn't initialized in async mode.
+func Flush() error {
+	if !initialized {
+		return NotInitializedErr
+	}
+	if f, ok := std.write.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// AsyncStats reports the state of the async queue installed by InitAsync.
+// ok is false when logz isn't running in async mode.
+func AsyncStats() (stats Stats, ok bool) {
+	aw, ok := std.write.(*asyncWriter)
+	if !ok {
+		return Stats{}, false
+	}
+	return aw.Stats(), true
+}
@@ -0,0 +1,136 @@
+package logz
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newUndrainedAsyncWriter builds an asyncWriter without starting its
+// background run() goroutine, so Write's overflow behavior can be observed
+// without racing a concurrent consumer draining the queue.
+func newUndrainedAsyncWriter(out *bytes.Buffer, size int, policy OverflowPolicy) *asyncWriter {
+	return &asyncWriter{
+		out:      out,
+		queue:    make(chan []byte, size),
+		overflow: policy,
+	}
+}
+
+func fillQueue(t *testing.T, w *asyncWriter, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := w.Write([]byte{byte('a' + i)}); err != nil {
+			t.Fatalf("priming write %d: %v", i, err)
+		}
+	}
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+	w := newUndrainedAsyncWriter(&bytes.Buffer{}, 2, DropNewest)
+	fillQueue(t, w, 2)
+
+	if _, err := w.Write([]byte("overflow")); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := w.Stats()
+	if stats.Dropped != 1 {
+		t.Fatalf("Dropped = %d, want 1", stats.Dropped)
+	}
+	if stats.Queued != 2 {
+		t.Fatalf("Queued = %d, want 2 (overflow record must not be enqueued)", stats.Queued)
+	}
+	if got := string(<-w.queue); got != "a" {
+		t.Fatalf("first queued record = %q, want %q (oldest must survive)", got, "a")
+	}
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+	w := newUndrainedAsyncWriter(&bytes.Buffer{}, 2, DropOldest)
+	fillQueue(t, w, 2)
+
+	if _, err := w.Write([]byte("newest")); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := w.Stats()
+	if stats.Dropped != 1 {
+		t.Fatalf("Dropped = %d, want 1", stats.Dropped)
+	}
+	if stats.Queued != 2 {
+		t.Fatalf("Queued = %d, want 2 (newest record must replace the oldest)", stats.Queued)
+	}
+	if got := string(<-w.queue); got != "b" {
+		t.Fatalf("first queued record = %q, want %q (oldest must have been evicted)", got, "b")
+	}
+}
+
+func TestAsyncWriterBlockCaller(t *testing.T) {
+	w := newUndrainedAsyncWriter(&bytes.Buffer{}, 1, BlockCaller)
+	fillQueue(t, w, 1)
+
+	done := make(chan struct{})
+	go func() {
+		w.Write([]byte("blocks"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("BlockCaller write returned before the queue had room")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-w.queue // drain one slot
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("BlockCaller write never unblocked once the queue drained")
+	}
+}
+
+// TestAsyncWriterFlushSynchronizesWithRun reproduces a race where run()'s
+// select and Flush()'s drain loop both read w.queue independently: run()
+// could pull a record into its local pending slice, invisible to a
+// concurrent Flush reading the now-empty channel, leaving it unwritten
+// until the next FlushInterval tick. With run() actually started (unlike
+// newUndrainedAsyncWriter's tests above), every Write immediately followed
+// by a Flush must make that record observable in out before Flush returns.
+func TestAsyncWriterFlushSynchronizesWithRun(t *testing.T) {
+	var out bytes.Buffer
+	w := newAsyncWriter(&out, AsyncOptions{BufferSize: 16, FlushInterval: time.Hour})
+	defer w.Close()
+
+	for i := 0; i < 500; i++ {
+		msg := fmt.Sprintf("<%d>", i)
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("flush %d: %v", i, err)
+		}
+		if got := out.String(); !strings.HasSuffix(got, msg) {
+			t.Fatalf("after Flush following write %d, out = %q, want suffix %q (run() stole the record without Flush seeing it)", i, got, msg)
+		}
+	}
+}
+
+func TestAsyncWriterFallbackSync(t *testing.T) {
+	var out bytes.Buffer
+	w := newUndrainedAsyncWriter(&out, 1, FallbackSync)
+	fillQueue(t, w, 1)
+
+	if _, err := w.Write([]byte("sync")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); got != "sync" {
+		t.Fatalf("out = %q, want %q (FallbackSync must bypass the queue)", got, "sync")
+	}
+	if dropped := w.Stats().Dropped; dropped != 0 {
+		t.Fatalf("Dropped = %d, want 0", dropped)
+	}
+}
@@ -0,0 +1,81 @@
+package logz
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	backtraceMu sync.RWMutex
+	backtraceAt map[string]struct{}
+)
+
+// SetBacktraceAt replaces the set of call sites that trigger a
+// debug.Stack() dump regardless of level, parsed from a comma-separated
+// "file:line,..." spec (glog/klog's -log_backtrace_at). file is matched by
+// base name, so it works across vendored or relocated trees.
+func SetBacktraceAt(spec string) error {
+	set := make(map[string]struct{})
+	for _, part := range strings.Split(spec, ",") {
+		if part == "" {
+			continue
+		}
+		idx := strings.LastIndex(part, ":")
+		if idx < 0 {
+			return fmt.Errorf("logz: invalid log_backtrace_at entry %q", part)
+		}
+		line, err := strconv.Atoi(part[idx+1:])
+		if err != nil {
+			return fmt.Errorf("logz: invalid log_backtrace_at line in %q: %w", part, err)
+		}
+		set[backtraceKey(part[:idx], line)] = struct{}{}
+	}
+
+	backtraceMu.Lock()
+	backtraceAt = set
+	backtraceMu.Unlock()
+	return nil
+}
+
+// AddBacktraceAt adds a single call site to the backtrace set without
+// disturbing entries already configured via SetBacktraceAt.
+func AddBacktraceAt(file string, line int) {
+	backtraceMu.Lock()
+	if backtraceAt == nil {
+		backtraceAt = make(map[string]struct{})
+	}
+	backtraceAt[backtraceKey(file, line)] = struct{}{}
+	backtraceMu.Unlock()
+}
+
+func backtraceKey(file string, line int) string {
+	return filepath.Base(file) + ":" + strconv.Itoa(line)
+}
+
+// shouldBacktrace reports whether the call site of a log function (Log,
+// Logf, Trace, ..., Critical, on either the package default or a named
+// Logger, or an Entry reached via WithFields/WithError) is in the
+// configured backtrace set. It assumes the fixed call chain
+// user -> Trace/Info/.../Entry.Info/... -> log/Entry.log -> (*Logger).logEntry -> shouldBacktrace.
+func shouldBacktrace() bool {
+	backtraceMu.RLock()
+	empty := len(backtraceAt) == 0
+	backtraceMu.RUnlock()
+	if empty {
+		return false
+	}
+
+	_, file, line, ok := runtime.Caller(4)
+	if !ok {
+		return false
+	}
+
+	backtraceMu.RLock()
+	_, hit := backtraceAt[backtraceKey(file, line)]
+	backtraceMu.RUnlock()
+	return hit
+}
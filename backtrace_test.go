@@ -0,0 +1,50 @@
+package logz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBacktraceAtTriggersOnlyConfiguredCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Init(&buf, LogLevelCritical, LogLevelTrace, LogLevelCritical, false); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		initialized = false
+	}()
+	defer SetBacktraceAt("")
+
+	if err := SetBacktraceAt("backtrace_test.go:29"); err != nil {
+		t.Fatal(err)
+	}
+
+	Info("no backtrace here") // not the configured line
+	if strings.Contains(buf.String(), "goroutine") {
+		t.Fatalf("unconfigured call site unexpectedly dumped a stack: %s", buf.String())
+	}
+	buf.Reset()
+
+	Info("marked line") // backtrace_test.go:29
+	if !strings.Contains(buf.String(), "goroutine") {
+		t.Fatalf("configured call site did not dump a stack: %s", buf.String())
+	}
+}
+
+func TestAddBacktraceAtAugmentsExistingSet(t *testing.T) {
+	if err := SetBacktraceAt("a.go:1"); err != nil {
+		t.Fatal(err)
+	}
+	AddBacktraceAt("b.go", 2)
+
+	backtraceMu.RLock()
+	_, hasA := backtraceAt[backtraceKey("a.go", 1)]
+	_, hasB := backtraceAt[backtraceKey("b.go", 2)]
+	backtraceMu.RUnlock()
+
+	if !hasA || !hasB {
+		t.Fatalf("expected both entries present, got %v", backtraceAt)
+	}
+	SetBacktraceAt("")
+}
@@ -0,0 +1,121 @@
+package logz
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Fields carries structured context attached via WithFields.
+type Fields map[string]interface{}
+
+// Entry carries structured context (fields and/or an error) through a
+// single log call, rendered by the owning Logger's Formatter instead of
+// the plain log.Logger prefix used by a bare Trace/Info/... call.
+type Entry struct {
+	Logger  string
+	Fields  Fields
+	Level   LogLevel
+	Message string
+	Time    time.Time
+	err     error
+
+	// logger is the Logger this Entry renders through. nil means the
+	// package-level default (std), for Entries created via the
+	// package-level WithFields/WithError.
+	logger *Logger
+}
+
+// WithFields returns an Entry bound to the default logger, carrying the
+// given structured fields, to be completed with one of
+// Info/Warning/Error/Critical(f).
+func WithFields(fields Fields) *Entry {
+	return std.WithFields(fields)
+}
+
+// WithError returns an Entry bound to the default logger, carrying err, to
+// be completed with one of Info/Warning/Error/Critical(f).
+func WithError(err error) *Entry {
+	return std.WithError(err)
+}
+
+func (e *Entry) Info(v ...interface{}) {
+	e.log(LogLevelInfo, fmt.Sprint(v...))
+}
+
+func (e *Entry) Infof(format string, v ...interface{}) {
+	e.log(LogLevelInfo, fmt.Sprintf(format, v...))
+}
+
+func (e *Entry) Warning(v ...interface{}) {
+	e.log(LogLevelWarning, fmt.Sprint(v...))
+}
+
+func (e *Entry) Warningf(format string, v ...interface{}) {
+	e.log(LogLevelWarning, fmt.Sprintf(format, v...))
+}
+
+func (e *Entry) Error(v ...interface{}) {
+	e.log(LogLevelError, fmt.Sprint(v...))
+}
+
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	e.log(LogLevelError, fmt.Sprintf(format, v...))
+}
+
+func (e *Entry) Critical(v ...interface{}) {
+	e.log(LogLevelCritical, fmt.Sprint(v...))
+	e.drain()
+	os.Exit(1)
+}
+
+func (e *Entry) Criticalf(format string, v ...interface{}) {
+	e.log(LogLevelCritical, fmt.Sprintf(format, v...))
+	e.drain()
+	os.Exit(1)
+}
+
+func (e *Entry) log(level LogLevel, msg string) {
+	l := e.logger
+	if l == nil {
+		l = std
+	}
+	if l == std && !initialized {
+		log.Print(NotInitializedErr)
+		return
+	}
+	l.logEntry(level, msg, e)
+}
+
+// drain flushes e's target logger before a fatal os.Exit (see
+// Entry.Critical/Criticalf), so queued async context isn't lost.
+func (e *Entry) drain() {
+	l := e.logger
+	if l == nil {
+		l = std
+	}
+	if l != nil {
+		l.drain()
+	}
+}
+
+// mergeFields returns a new Fields combining base and extra, with extra's
+// keys winning on conflict. Either may be nil/empty, in which case the
+// other is returned as-is.
+func mergeFields(base, extra Fields) Fields {
+	if len(base) == 0 {
+		return extra
+	}
+	if len(extra) == 0 {
+		return base
+	}
+	merged := make(Fields, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,155 @@
+package logz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Formatter renders an Entry into the bytes that get written to the log
+// output. TextFormatter reproduces the classic "LEVEL| timestamp message"
+// output; JSONFormatter emits one JSON object per line for machine parsing.
+type Formatter interface {
+	Format(e *Entry) ([]byte, error)
+}
+
+// TextFormatter is the default Formatter, matching the historic
+// "TRACE|2006/01/02 15:04:05 message" output produced via log.Logger.
+type TextFormatter struct{}
+
+func (f *TextFormatter) Format(e *Entry) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	buf.WriteString(getLogPrefix(e.Level))
+	if e.Logger != "" {
+		buf.WriteString(e.Logger)
+		buf.WriteString("| ")
+	}
+	buf.WriteString(e.Time.Format("2006/01/02 15:04:05 "))
+	buf.WriteString(e.Message)
+	if e.err != nil {
+		fmt.Fprintf(buf, " error=%q", e.err.Error())
+	}
+	for k, v := range e.Fields {
+		fmt.Fprintf(buf, " %s=%v", k, v)
+	}
+	buf.WriteByte('\n')
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out, nil
+}
+
+// JSONFormatter emits each Entry as a single-line JSON object. It writes
+// directly into a pooled buffer instead of building a map and handing it to
+// encoding/json, so a record with only string/numeric/bool fields (the
+// common case) encodes without the per-call map and encoder allocations
+// that approach would otherwise cost; see BenchmarkJSONFormatterFormat.
+type JSONFormatter struct{}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func (f *JSONFormatter) Format(e *Entry) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	buf.WriteByte('{')
+	writeJSONKey(buf, "level")
+	appendJSONString(buf, getLogPrefix(e.Level))
+	buf.WriteByte(',')
+	if e.Logger != "" {
+		writeJSONKey(buf, "logger")
+		appendJSONString(buf, e.Logger)
+		buf.WriteByte(',')
+	}
+	writeJSONKey(buf, "time")
+	appendJSONString(buf, e.Time.Format(time.RFC3339))
+	buf.WriteByte(',')
+	writeJSONKey(buf, "msg")
+	appendJSONString(buf, e.Message)
+	if e.err != nil {
+		buf.WriteByte(',')
+		writeJSONKey(buf, "error")
+		appendJSONString(buf, e.err.Error())
+	}
+	for k, v := range e.Fields {
+		buf.WriteByte(',')
+		writeJSONKey(buf, k)
+		if err := appendJSONValue(buf, v); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("}\n")
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out, nil
+}
+
+func writeJSONKey(buf *bytes.Buffer, key string) {
+	appendJSONString(buf, key)
+	buf.WriteByte(':')
+}
+
+// appendJSONString quotes and escapes s directly into buf, avoiding the
+// allocation json.Marshal would make for the plain strings (level, time,
+// msg, field keys) that dominate a typical record.
+func appendJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20:
+			fmt.Fprintf(buf, `\u%04x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// appendJSONValue renders a field value into buf. Common scalar types are
+// appended directly; anything else falls back to json.Marshal, which is the
+// only case that still allocates.
+func appendJSONValue(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case string:
+		appendJSONString(buf, val)
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case int:
+		buf.WriteString(strconv.Itoa(val))
+	case int64:
+		buf.WriteString(strconv.FormatInt(val, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+	case error:
+		appendJSONString(buf, val.Error())
+	case nil:
+		buf.WriteString("null")
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
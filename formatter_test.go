@@ -0,0 +1,61 @@
+package logz
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatterFormat(t *testing.T) {
+	e := &Entry{
+		Level:   LogLevelError,
+		Message: "boom",
+		Time:    time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Fields:  Fields{"count": 3, "ok": false, "path": "a\"b"},
+	}
+	e.err = errors.New("wrapped")
+
+	b, err := (&JSONFormatter{}).Format(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, b)
+	}
+
+	if got["msg"] != "boom" {
+		t.Errorf("msg = %v, want boom", got["msg"])
+	}
+	if got["error"] != "wrapped" {
+		t.Errorf("error = %v, want wrapped", got["error"])
+	}
+	if got["path"] != "a\"b" {
+		t.Errorf("path = %v, want a\"b", got["path"])
+	}
+	if got["count"].(float64) != 3 {
+		t.Errorf("count = %v, want 3", got["count"])
+	}
+}
+
+// BenchmarkJSONFormatterFormat exercises the common case of scalar fields,
+// where appendJSONValue never falls back to json.Marshal.
+func BenchmarkJSONFormatterFormat(b *testing.B) {
+	f := &JSONFormatter{}
+	e := &Entry{
+		Level:   LogLevelInfo,
+		Message: "request handled",
+		Time:    time.Now(),
+		Fields:  Fields{"status": 200, "path": "/health", "cached": true},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Format(e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
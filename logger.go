@@ -0,0 +1,265 @@
+package logz
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Options configures a Logger, mirroring Init's parameters.
+type Options struct {
+	Out        io.Writer
+	StdLevel   LogLevel
+	OutLevel   LogLevel
+	StackLevel LogLevel
+	// LogFileName prepends the call site ("file.go:42: ") to every
+	// record's message, the Formatter-based equivalent of log.Lshortfile.
+	LogFileName bool
+	// Formatter renders every record this Logger produces, both plain
+	// Trace/Info/... calls and Entry-based ones (WithFields/WithError).
+	// Defaults to &TextFormatter{}.
+	Formatter Formatter
+}
+
+// Logger is an independently configured log stream: its own levels,
+// writer, formatter, and stack threshold. The package-level functions
+// (Trace, Info, ...) are a thin wrapper over a default Logger set up by
+// Init, so existing callers are unaffected by named sub-loggers.
+type Logger struct {
+	name string
+
+	mu          sync.RWMutex
+	stackLevel  LogLevel
+	stdLevel    LogLevel
+	outLevel    LogLevel
+	write       io.Writer
+	formatter   Formatter
+	logFileName bool
+	fields      Fields
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Logger{}
+)
+
+// New creates a named Logger. name prefixes every line it writes (rendered
+// by the Formatter, e.g. TextFormatter's " INFO|auth| message"); an empty
+// name reproduces the package-level default's output exactly.
+func New(name string, opts Options) *Logger {
+	l := &Logger{name: name}
+	l.configure(opts)
+	return l
+}
+
+func (l *Logger) configure(opts Options) {
+	if opts.Formatter == nil {
+		opts.Formatter = &TextFormatter{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stackLevel = opts.StackLevel
+	l.stdLevel = opts.StdLevel
+	l.outLevel = opts.OutLevel
+	l.write = opts.Out
+	l.formatter = opts.Formatter
+	l.logFileName = opts.LogFileName
+}
+
+// SetLevel raises or lowers both the stdout and output thresholds to level
+// in one call, the common case of wanting a single verbosity knob. For
+// split stdout/output control, reconfigure with New/Options instead.
+func (l *Logger) SetLevel(level LogLevel) {
+	l.mu.RLock()
+	opts := Options{
+		Out:         l.write,
+		StdLevel:    level,
+		OutLevel:    level,
+		StackLevel:  l.stackLevel,
+		LogFileName: l.logFileName,
+		Formatter:   l.formatter,
+	}
+	l.mu.RUnlock()
+	l.configure(opts)
+}
+
+// With returns a child Logger that merges fields into every subsequent
+// record, in addition to any already attached via an earlier With.
+func (l *Logger) With(fields Fields) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{
+		name:        l.name,
+		stackLevel:  l.stackLevel,
+		stdLevel:    l.stdLevel,
+		outLevel:    l.outLevel,
+		write:       l.write,
+		formatter:   l.formatter,
+		logFileName: l.logFileName,
+		fields:      merged,
+	}
+}
+
+// Sub returns a named child logger nested under l's name (e.g. l named
+// "auth", Sub("token") produces "auth.token"). It snapshots l's writer,
+// formatter and levels at call time; a later l.SetLevel does not
+// retroactively affect children already created via Sub.
+func (l *Logger) Sub(name string) *Logger {
+	l.mu.RLock()
+	opts := Options{
+		Out:         l.write,
+		StdLevel:    l.stdLevel,
+		OutLevel:    l.outLevel,
+		StackLevel:  l.stackLevel,
+		LogFileName: l.logFileName,
+		Formatter:   l.formatter,
+	}
+	fields := l.fields
+	l.mu.RUnlock()
+
+	full := name
+	if l.name != "" {
+		full = l.name + "." + name
+	}
+	child := New(full, opts)
+	if len(fields) > 0 {
+		child = child.With(fields)
+	}
+	return child
+}
+
+// WithFields returns an Entry bound to l, carrying the given structured
+// fields, to be completed with one of Info/Warning/Error/Critical(f). The
+// entry renders through l's formatter, so a Logger configured with e.g.
+// JSONFormatter produces JSON from its named sub-loggers too.
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return &Entry{Fields: fields, logger: l}
+}
+
+// WithError returns an Entry bound to l, carrying err, to be completed with
+// one of Info/Warning/Error/Critical(f).
+func (l *Logger) WithError(err error) *Entry {
+	return &Entry{Fields: Fields{}, err: err, logger: l}
+}
+
+func (l *Logger) log(level LogLevel, msg string) {
+	if l.logFileName {
+		msg = callerPrefix(3) + msg
+	}
+	l.logEntry(level, msg, &Entry{})
+}
+
+// logEntry renders e through l's formatter and writes it to stdout/l.write
+// per l's level thresholds, merging in any fields attached via With. It's
+// the single rendering path shared by plain Trace/Info/... calls (which
+// pass a bare *Entry) and Entry-based ones reached via WithFields/WithError.
+func (l *Logger) logEntry(level LogLevel, msg string, e *Entry) {
+	l.mu.RLock()
+	formatter := l.formatter
+	stdLevel := l.stdLevel
+	outLevel := l.outLevel
+	stackLevel := l.stackLevel
+	write := l.write
+	fields := l.fields
+	l.mu.RUnlock()
+
+	e.Logger = l.name
+	e.Level = level
+	e.Message = msg
+	e.Time = time.Now()
+	e.Fields = mergeFields(fields, e.Fields)
+
+	b, err := formatter.Format(e)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if level >= stdLevel {
+		os.Stdout.Write(b)
+	}
+	if level >= outLevel && write != nil {
+		write.Write(b)
+	}
+	if (level >= stackLevel || shouldBacktrace()) && write != nil {
+		fmt.Fprintln(write, string(debug.Stack()))
+	}
+}
+
+// callerPrefix renders the "file.go:line: " prefix LogFileName adds, skip
+// frames up from its own call site.
+func callerPrefix(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d: ", filepath.Base(file), line)
+}
+
+func (l *Logger) Trace(v ...interface{}) {
+	l.log(LogLevelTrace, fmt.Sprint(v...))
+}
+
+func (l *Logger) Tracef(format string, v ...interface{}) {
+	l.log(LogLevelTrace, fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) Info(v ...interface{}) {
+	l.log(LogLevelInfo, fmt.Sprint(v...))
+}
+
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.log(LogLevelInfo, fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) Warning(v ...interface{}) {
+	l.log(LogLevelWarning, fmt.Sprint(v...))
+}
+
+func (l *Logger) Warningf(format string, v ...interface{}) {
+	l.log(LogLevelWarning, fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) Error(v ...interface{}) {
+	l.log(LogLevelError, fmt.Sprint(v...))
+}
+
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.log(LogLevelError, fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) Critical(v ...interface{}) {
+	l.log(LogLevelCritical, fmt.Sprint(v...))
+	l.drain()
+	os.Exit(1)
+}
+
+func (l *Logger) Criticalf(format string, v ...interface{}) {
+	l.log(LogLevelCritical, fmt.Sprintf(format, v...))
+	l.drain()
+	os.Exit(1)
+}
+
+// drain flushes l's writer if it buffers records (e.g. the writer InitAsync
+// installs), so a fatal Critical/Criticalf doesn't lose queued context.
+func (l *Logger) drain() {
+	l.mu.RLock()
+	w := l.write
+	l.mu.RUnlock()
+	if f, ok := w.(Flusher); ok {
+		f.Flush()
+	}
+}
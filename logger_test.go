@@ -0,0 +1,128 @@
+package logz
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestLoggerCriticalDrainsAsync reproduces the bug where Package(name).Critical
+// exited before the async writer flushed its queue, silently dropping the
+// fatal message. It re-execs itself as a subprocess since Critical calls
+// os.Exit, and checks the file written by that subprocess afterwards.
+func TestLoggerCriticalDrainsAsync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "critical.log")
+
+	if os.Getenv("LOGZ_CRITICAL_HELPER") == "1" {
+		f, err := os.Create(os.Getenv("LOGZ_CRITICAL_PATH"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitAsync(f, LogLevelCritical, LogLevelTrace, LogLevelCritical, false, AsyncOptions{FlushInterval: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		Package("auth").Critical("boom")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestLoggerCriticalDrainsAsync")
+	cmd.Env = append(os.Environ(), "LOGZ_CRITICAL_HELPER=1", "LOGZ_CRITICAL_PATH="+path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			t.Fatalf("helper process failed: %v\n%s", err, out)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading helper output: %v", err)
+	}
+	if !bytes.Contains(got, []byte("boom")) {
+		t.Fatalf("expected Critical to drain the async queue before exiting, got: %q", got)
+	}
+}
+
+// TestPackageLoggerHonorsFormatter reproduces a bug where a named
+// sub-logger (via Package/Sub) stored the Formatter passed to
+// InitWithFormatter but never rendered through it: Logger.log() only ever
+// produced the classic "LEVEL|name| ..." text, so there was no way to get
+// structured/JSON output out of any named logger at all.
+func TestPackageLoggerHonorsFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := InitWithFormatter(&buf, LogLevelCritical, LogLevelTrace, LogLevelCritical, false, &JSONFormatter{}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		initialized = false
+		registry = map[string]*Logger{}
+	}()
+
+	Package("auth").Info("hello")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Package(\"auth\").Info output is not valid JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got["logger"] != "auth" {
+		t.Errorf("logger = %v, want %q", got["logger"], "auth")
+	}
+	if got["msg"] != "hello" {
+		t.Errorf("msg = %v, want hello", got["msg"])
+	}
+}
+
+// TestLoggerWithFieldsRendersThroughFormatter checks that fields attached
+// via (*Logger).With flow into the same formatter-rendered record as
+// everything else, rather than the old plain "k=v" string concatenation
+// that would have corrupted a JSON-formatted logger's output.
+func TestLoggerWithFieldsRendersThroughFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := InitWithFormatter(&buf, LogLevelCritical, LogLevelTrace, LogLevelCritical, false, &JSONFormatter{}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		initialized = false
+		registry = map[string]*Logger{}
+	}()
+
+	Package("auth").With(Fields{"request_id": "r-1"}).Info("hi")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got["request_id"] != "r-1" {
+		t.Errorf("request_id = %v, want r-1", got["request_id"])
+	}
+}
+
+// TestLoggerWithFieldsEntryBindsToOwningLogger checks that
+// (*Logger).WithFields/WithError render through that logger's own
+// formatter and writer, not always the package-level default.
+func TestLoggerWithFieldsEntryBindsToOwningLogger(t *testing.T) {
+	var buf bytes.Buffer
+	if err := InitWithFormatter(&buf, LogLevelCritical, LogLevelTrace, LogLevelCritical, false, &JSONFormatter{}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		initialized = false
+		registry = map[string]*Logger{}
+	}()
+
+	Package("auth").WithFields(Fields{"count": 3}).Info("hi")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got["logger"] != "auth" {
+		t.Errorf("logger = %v, want auth", got["logger"])
+	}
+	if got["count"].(float64) != 3 {
+		t.Errorf("count = %v, want 3", got["count"])
+	}
+}
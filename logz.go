@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
-	"runtime/debug"
 )
 
 var (
@@ -16,13 +14,7 @@ var (
 
 var (
 	initialized bool
-	lTrace      *log.Logger
-	lInfo       *log.Logger
-	lWarning    *log.Logger
-	lError      *log.Logger
-	lCritical   *log.Logger
-	logStack    LogLevel
-	write       io.Writer
+	std         *Logger
 )
 
 type LogLevel byte
@@ -35,23 +27,28 @@ const (
 	LogLevelCritical
 )
 
-func Init(out io.Writer, logStdLevel, logOutLevel, stackLevel LogLevel, logFileName bool) error {
+// Init initializes logz with the classic text output. It is a backward
+// compatible wrapper over InitWithFormatter using &TextFormatter{}.
+func Init(out io.Writer, logStdLvl, logOutLvl, stackLevel LogLevel, logFileName bool) error {
+	return InitWithFormatter(out, logStdLvl, logOutLvl, stackLevel, logFileName, &TextFormatter{})
+}
+
+// InitWithFormatter initializes logz like Init, but renders every record
+// (plain Trace/Info/... calls and Entry-based ones via WithFields/WithError)
+// through the given Formatter instead of the built-in text format.
+func InitWithFormatter(out io.Writer, logStdLvl, logOutLvl, stackLevel LogLevel, logFileName bool, f Formatter) error {
 	if initialized {
 		return InitializedErr
 	}
 
-	flags := log.LstdFlags
-	if logFileName {
-		flags |= log.Lshortfile
-	}
-	lTrace = newLogger(LogLevelTrace, out, logStdLevel, logOutLevel, flags)
-	lInfo = newLogger(LogLevelInfo, out, logStdLevel, logOutLevel, flags)
-	lWarning = newLogger(LogLevelWarning, out, logStdLevel, logOutLevel, flags)
-	lError = newLogger(LogLevelError, out, logStdLevel, logOutLevel, flags)
-	lCritical = newLogger(LogLevelCritical, out, logStdLevel, logOutLevel, flags)
-	logStack = stackLevel
-
-	write = out
+	std = New("", Options{
+		Out:         out,
+		StdLevel:    logStdLvl,
+		OutLevel:    logOutLvl,
+		StackLevel:  stackLevel,
+		LogFileName: logFileName,
+		Formatter:   f,
+	})
 	initialized = true
 	return nil
 }
@@ -71,8 +68,8 @@ func Close() error {
 		Log(LogLevelCritical, err)
 	}
 	initialized = false
-	if close, ok := write.(io.Closer); ok {
-		close.Close()
+	if c, ok := std.write.(io.Closer); ok {
+		c.Close()
 	}
 	if r != nil {
 		panic(r) //rethrow from here
@@ -85,13 +82,7 @@ func Log(level LogLevel, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	log := getLogger(level)
-	if log != nil {
-		log.Output(2, fmt.Sprint(v...))
-	}
-	if level >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(level, fmt.Sprint(v...))
 }
 
 func Logf(level LogLevel, format string, v ...interface{}) {
@@ -99,13 +90,7 @@ func Logf(level LogLevel, format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	log := getLogger(level)
-	if log != nil {
-		log.Output(2, fmt.Sprintf(format, v...))
-	}
-	if level >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(level, fmt.Sprintf(format, v...))
 }
 
 func Trace(v ...interface{}) {
@@ -113,12 +98,7 @@ func Trace(v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lTrace != nil {
-		lTrace.Output(2, fmt.Sprint(v...))
-	}
-	if LogLevelTrace >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelTrace, fmt.Sprint(v...))
 }
 
 func Tracef(format string, v ...interface{}) {
@@ -126,12 +106,7 @@ func Tracef(format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lTrace != nil {
-		lTrace.Output(2, fmt.Sprintf(format, v...))
-	}
-	if LogLevelTrace >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelTrace, fmt.Sprintf(format, v...))
 }
 
 func Info(v ...interface{}) {
@@ -139,12 +114,7 @@ func Info(v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lInfo != nil {
-		lInfo.Output(2, fmt.Sprint(v...))
-	}
-	if LogLevelInfo >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelInfo, fmt.Sprint(v...))
 }
 
 func Infof(format string, v ...interface{}) {
@@ -152,12 +122,7 @@ func Infof(format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lInfo != nil {
-		lInfo.Output(2, fmt.Sprintf(format, v...))
-	}
-	if LogLevelInfo >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelInfo, fmt.Sprintf(format, v...))
 }
 
 func Warning(v ...interface{}) {
@@ -165,12 +130,7 @@ func Warning(v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lWarning != nil {
-		lWarning.Output(2, fmt.Sprint(v...))
-	}
-	if LogLevelWarning >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelWarning, fmt.Sprint(v...))
 }
 
 func Warningf(format string, v ...interface{}) {
@@ -178,12 +138,7 @@ func Warningf(format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lWarning != nil {
-		lWarning.Output(2, fmt.Sprintf(format, v...))
-	}
-	if LogLevelWarning >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelWarning, fmt.Sprintf(format, v...))
 }
 
 func Error(v ...interface{}) {
@@ -191,12 +146,7 @@ func Error(v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lError != nil {
-		lError.Output(2, fmt.Sprint(v...))
-	}
-	if LogLevelError >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelError, fmt.Sprint(v...))
 }
 
 func Errorf(format string, v ...interface{}) {
@@ -204,12 +154,7 @@ func Errorf(format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lError != nil {
-		lError.Output(2, fmt.Sprintf(format, v...))
-	}
-	if LogLevelError >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
+	std.log(LogLevelError, fmt.Sprintf(format, v...))
 }
 
 func Critical(v ...interface{}) {
@@ -217,13 +162,7 @@ func Critical(v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lCritical != nil {
-		lCritical.Output(2, fmt.Sprint(v...))
-	}
-	if LogLevelCritical >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
-	}
-	os.Exit(1)
+	std.Critical(v...)
 }
 
 func Criticalf(format string, v ...interface{}) {
@@ -231,13 +170,21 @@ func Criticalf(format string, v ...interface{}) {
 		log.Print(NotInitializedErr)
 		return
 	}
-	if lCritical != nil {
-		lCritical.Output(2, fmt.Sprintf(format, v...))
-	}
-	if LogLevelCritical >= logStack {
-		fmt.Fprintln(write, string(debug.Stack()))
+	std.Criticalf(format, v...)
+}
+
+// Package returns the named sub-logger of the default logger, creating it
+// on first use from std's current configuration. Repeated calls with the
+// same name return the same *Logger.
+func Package(name string) *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if l, ok := registry[name]; ok {
+		return l
 	}
-	os.Exit(1)
+	l := std.Sub(name)
+	registry[name] = l
+	return l
 }
 
 func GetLogLevel(str string) LogLevel {
@@ -276,38 +223,3 @@ func getLogPrefix(level LogLevel) string {
 		return ""
 	}
 }
-
-func newLogger(level LogLevel, out io.Writer, logStdLevel, logOutLevel LogLevel, flags int) *log.Logger {
-	var w io.Writer
-	if level >= logStdLevel {
-		w = os.Stdout
-	}
-	if level >= logOutLevel && out != w {
-		if w != nil {
-			w = io.MultiWriter(w, out)
-		} else {
-			w = out
-		}
-	}
-	if w == nil {
-		return nil
-	}
-	return log.New(w, getLogPrefix(level), flags)
-}
-
-func getLogger(level LogLevel) *log.Logger {
-	switch level {
-	case LogLevelTrace:
-		return lTrace
-	case LogLevelInfo:
-		return lInfo
-	case LogLevelWarning:
-		return lWarning
-	case LogLevelError:
-		return lError
-	case LogLevelCritical:
-		return lCritical
-	default:
-		panic(fmt.Sprintf("Invalid log level %v", level))
-	}
-}
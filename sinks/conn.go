@@ -0,0 +1,191 @@
+package sinks
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ReconnectPolicy controls when a connWriter re-dials the remote after a
+// failed write.
+type ReconnectPolicy byte
+
+const (
+	// ReconnectOnMsg re-dials lazily, on the next Write after a failure.
+	ReconnectOnMsg ReconnectPolicy = iota
+	// Reconnect proactively re-dials in the background as soon as the
+	// connection drops, so the ring buffer is flushed without waiting for
+	// the next log line.
+	Reconnect
+)
+
+// ConnOpts configures NewConnWriter.
+type ConnOpts struct {
+	Policy      ReconnectPolicy
+	DialTimeout time.Duration
+	Backoff     Backoff
+	// RingSize is the number of bytes buffered while disconnected. Defaults
+	// to 64KiB.
+	RingSize int
+}
+
+type connWriter struct {
+	mu      sync.Mutex
+	network string
+	addr    string
+	opts    ConnOpts
+	conn    net.Conn
+	ring    *ringBuffer
+	closed  bool
+	dialing bool
+}
+
+var errClosed = errors.New("sinks: writer closed")
+
+// NewConnWriter returns an io.WriteCloser that streams writes to addr over
+// network ("tcp" or "udp"). It survives transient failures by buffering the
+// last opts.RingSize bytes in a ring during a disconnect and flushing them
+// on reconnect, rather than silently dropping them like the bare
+// logz.MultiWriter does.
+func NewConnWriter(network, addr string, opts ConnOpts) *connWriter {
+	if opts.Backoff == nil {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	w := &connWriter{
+		network: network,
+		addr:    addr,
+		opts:    opts,
+		ring:    newRingBuffer(opts.RingSize),
+	}
+	if conn, err := net.DialTimeout(network, addr, opts.DialTimeout); err == nil {
+		w.conn = conn
+	} else if opts.Policy == Reconnect {
+		w.startReconnectLoop()
+	}
+	return w
+}
+
+func (w *connWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errClosed
+	}
+	if w.conn == nil {
+		w.ring.Write(p)
+		// Both policies redial off this goroutine via startReconnectLoop's
+		// backoff, so a downed remote never blocks the hot path; the only
+		// difference is when the first attempt is kicked off: Reconnect
+		// does it as soon as the drop is detected, ReconnectOnMsg waits
+		// for the next Write to notice it.
+		w.startReconnectLoop()
+		return len(p), nil
+	}
+	if _, err := w.conn.Write(p); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		w.ring.Write(p)
+		if w.opts.Policy == Reconnect {
+			w.startReconnectLoop()
+		}
+	}
+	return len(p), nil
+}
+
+// tryDialLocked makes a single best-effort reconnect attempt and, on
+// success, flushes the ring. Callers hold w.mu.
+func (w *connWriter) tryDialLocked() {
+	conn, err := net.DialTimeout(w.network, w.addr, w.opts.DialTimeout)
+	if err != nil {
+		return
+	}
+	w.conn = conn
+	w.flushRingLocked()
+}
+
+func (w *connWriter) flushRingLocked() {
+	if w.ring.len == 0 || w.conn == nil {
+		return
+	}
+	if _, err := w.conn.Write(w.ring.Bytes()); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return
+	}
+	w.ring.Reset()
+}
+
+func (w *connWriter) startReconnectLoop() {
+	if w.dialing {
+		return
+	}
+	w.dialing = true
+	go func() {
+		for attempt := 0; ; attempt++ {
+			time.Sleep(w.opts.Backoff(attempt))
+
+			w.mu.Lock()
+			if w.closed || w.conn != nil {
+				w.dialing = false
+				w.mu.Unlock()
+				return
+			}
+			w.mu.Unlock()
+
+			conn, err := net.DialTimeout(w.network, w.addr, w.opts.DialTimeout)
+			if err != nil {
+				continue
+			}
+
+			w.mu.Lock()
+			if w.closed {
+				conn.Close()
+				w.dialing = false
+				w.mu.Unlock()
+				return
+			}
+			w.conn = conn
+			w.flushRingLocked()
+			w.dialing = false
+			w.mu.Unlock()
+			return
+		}
+	}()
+}
+
+// Flush pushes any ring-buffered bytes to the remote, dialing first if
+// currently disconnected.
+func (w *connWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		w.tryDialLocked()
+	} else {
+		w.flushRingLocked()
+	}
+	if w.ring.len > 0 {
+		return errors.New("sinks: unable to flush, remote unreachable")
+	}
+	return nil
+}
+
+func (w *connWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.conn != nil {
+		w.flushRingLocked()
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
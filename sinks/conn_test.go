@@ -0,0 +1,85 @@
+package sinks
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnWriterReconnectOnMsgDoesNotBlock(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() // nothing is listening; every dial attempt fails
+
+	w := NewConnWriter("tcp", addr, ConnOpts{
+		DialTimeout: 50 * time.Millisecond,
+		Backoff:     func(int) time.Duration { return 10 * time.Millisecond },
+	})
+	defer w.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			w.Write([]byte("x"))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on the hot path while the remote was down")
+	}
+}
+
+func TestConnWriterFlushesRingOnReconnect(t *testing.T) {
+	// Reserve an address, then free it again so NewConnWriter's initial
+	// dial fails and the writer starts disconnected.
+	reserve, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := reserve.Addr().String()
+	reserve.Close()
+
+	w := NewConnWriter("tcp", addr, ConnOpts{
+		DialTimeout: 200 * time.Millisecond,
+		Backoff:     func(int) time.Duration { return 10 * time.Millisecond },
+	})
+	defer w.Close()
+
+	w.mu.Lock()
+	hadConn := w.conn != nil
+	w.mu.Unlock()
+	if hadConn {
+		t.Fatal("expected connWriter to start disconnected")
+	}
+
+	if _, err := w.Write([]byte("buffered")); err != nil {
+		t.Fatalf("Write while disconnected: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	srvConn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	srvConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, len("buffered"))
+	if _, err := net.Conn.Read(srvConn, buf); err != nil {
+		t.Fatalf("reading flushed ring contents: %v", err)
+	}
+	if string(buf) != "buffered" {
+		t.Fatalf("got %q, want %q", buf, "buffered")
+	}
+}
@@ -0,0 +1,220 @@
+package sinks
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RotateInterval is a time-based rotation period for NewFileWriter.
+type RotateInterval byte
+
+const (
+	// NoInterval disables time-based rotation; only FileOpts.MaxBytes (if
+	// set) triggers a rotation.
+	NoInterval RotateInterval = iota
+	Hourly
+	Daily
+)
+
+// FileOpts configures NewFileWriter.
+type FileOpts struct {
+	// MaxBytes rotates the file once its size reaches this many bytes. Zero
+	// disables size-based rotation.
+	MaxBytes int64
+	Interval RotateInterval
+	// Gzip compresses a file once it's rotated out.
+	Gzip bool
+	// MaxBackups caps the number of rotated files kept, oldest first.
+	// Zero keeps them all.
+	MaxBackups int
+	// MaxAge removes rotated files older than this. Zero disables it.
+	MaxAge time.Duration
+}
+
+// fileWriter is an io.WriteCloser that writes to a timestamped file named
+// "path.YYYYMMDD-HHMMSS.log[.gz]" and keeps a symlink at path pointing to
+// whichever one is current, in the glog/klog convention.
+type fileWriter struct {
+	path string
+	opts FileOpts
+
+	mu      sync.Mutex
+	file    atomic.Value // *os.File
+	curName string
+	size    int64
+	// opened is time.Time.UnixNano(), 0 meaning unset. shouldRotate reads
+	// it from Write's lock-free fast path, so it's handled with the same
+	// atomic discipline as size rather than guarded by mu like curName.
+	opened int64
+}
+
+// NewFileWriter opens (or rotates into) a fresh timestamped log file and
+// returns a WriteCloser. Writes stay lock-free on the fast path; rotation
+// takes the mutex only once the size or time threshold is crossed.
+func NewFileWriter(path string, opts FileOpts) (*fileWriter, error) {
+	w := &fileWriter{path: path, opts: opts}
+	if err := w.rotate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	f := w.file.Load().(*os.File)
+	n, err := f.Write(p)
+	newSize := atomic.AddInt64(&w.size, int64(n))
+
+	if w.shouldRotate(newSize) {
+		w.mu.Lock()
+		if w.shouldRotate(atomic.LoadInt64(&w.size)) {
+			if rerr := w.rotate(); rerr != nil && err == nil {
+				err = rerr
+			}
+		}
+		w.mu.Unlock()
+	}
+	return n, err
+}
+
+func (w *fileWriter) shouldRotate(size int64) bool {
+	if w.opts.MaxBytes > 0 && size >= w.opts.MaxBytes {
+		return true
+	}
+	opened := atomic.LoadInt64(&w.opened)
+	if opened == 0 {
+		return false
+	}
+	since := time.Since(time.Unix(0, opened))
+	switch w.opts.Interval {
+	case Hourly:
+		return since >= time.Hour
+	case Daily:
+		return since >= 24*time.Hour
+	default:
+		return false
+	}
+}
+
+// rotate closes the current file (if any), opens a new timestamped file,
+// repoints the stable symlink at it, compresses the outgoing file, and
+// prunes old backups. Callers hold w.mu, except for the initial open from
+// NewFileWriter.
+func (w *fileWriter) rotate() error {
+	prev, _ := w.file.Load().(*os.File)
+	prevName := w.curName
+	if prev != nil {
+		prev.Close()
+	}
+
+	name := fmt.Sprintf("%s.%s.log", w.path, time.Now().Format("20060102-150405"))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Store the new file before touching the symlink: a symlink failure
+	// (e.g. an unsupported filesystem) must degrade rotation, not leave
+	// w.file pointing at prev, which is already closed above.
+	w.file.Store(f)
+	w.curName = name
+	atomic.StoreInt64(&w.size, 0)
+	atomic.StoreInt64(&w.opened, time.Now().UnixNano())
+
+	if prev != nil {
+		go w.finalize(prevName)
+	}
+
+	os.Remove(w.path)
+	if err := os.Symlink(filepath.Base(name), w.path); err != nil {
+		return err
+	}
+	return nil
+}
+
+// finalize optionally compresses a just-rotated-out file and prunes old
+// backups. It runs off the hot path since gzip and directory scans are too
+// slow to do under w.mu.
+func (w *fileWriter) finalize(name string) {
+	if w.opts.Gzip {
+		if err := gzipFile(name); err == nil {
+			os.Remove(name)
+		}
+	}
+	w.pruneBackups()
+}
+
+func gzipFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(name + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+func (w *fileWriter) pruneBackups() {
+	if w.opts.MaxBackups <= 0 && w.opts.MaxAge <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(w.path + ".*.log*")
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		name    string
+		modTime time.Time
+	}
+	backups := make([]backup, 0, len(matches))
+	for _, m := range matches {
+		info, err := os.Lstat(m)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{m, info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.Before(backups[j].modTime) })
+
+	cutoff := time.Now().Add(-w.opts.MaxAge)
+	for i, b := range backups {
+		tooMany := w.opts.MaxBackups > 0 && len(backups)-i > w.opts.MaxBackups
+		tooOld := w.opts.MaxAge > 0 && b.modTime.Before(cutoff)
+		if tooMany || tooOld {
+			os.Remove(b.name)
+		}
+	}
+}
+
+// Flush is a no-op; fileWriter writes straight through to the OS.
+func (w *fileWriter) Flush() error {
+	return nil
+}
+
+func (w *fileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	f, _ := w.file.Load().(*os.File)
+	if f == nil {
+		return nil
+	}
+	return f.Close()
+}
@@ -0,0 +1,255 @@
+package sinks
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFileWriterSurvivesSymlinkFailure reproduces a bug where a rotation
+// whose symlink step failed left w.file pointing at the just-closed
+// previous file, bricking every subsequent Write until the process
+// restarted.
+func TestFileWriterSurvivesSymlinkFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, FileOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+
+	// Force the next rotation's symlink step to fail: os.Remove(w.path)
+	// can't remove a non-empty directory, so os.Symlink then fails because
+	// the target already exists.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "blocker"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.rotate(); err == nil {
+		t.Fatal("expected rotate to fail with the symlink path occupied by a directory")
+	}
+
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("write after failed rotation should still succeed, got: %v", err)
+	}
+}
+
+func TestFileWriterRotatesOnMaxBytesAndRepointsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, FileOpts{MaxBytes: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// The backup filename is second-granular; without this, the rotation
+	// triggered by the write below could land in the same wall-clock
+	// second as NewFileWriter's initial open and collide with it.
+	time.Sleep(1100 * time.Millisecond)
+
+	if _, err := w.Write([]byte("1234")); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := os.Readlink(path)
+	if err != nil {
+		t.Fatalf("path should be a symlink after rotation: %v", err)
+	}
+
+	// The glob matches every timestamped file, both the rotated-out backup
+	// and the new current one (the symlink is what distinguishes them).
+	matches, err := filepath.Glob(path + ".*.log*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 timestamped files (1 backup + 1 current), got %d: %v", len(matches), matches)
+	}
+	var backupPath string
+	for _, m := range matches {
+		if filepath.Base(m) != target {
+			backupPath = m
+		}
+	}
+	if backupPath == "" {
+		t.Fatalf("could not find the rotated-out backup among %v (current is %s)", matches, target)
+	}
+
+	backup, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != "1234" {
+		t.Fatalf("backup contents = %q, want %q", backup, "1234")
+	}
+
+	// A write that doesn't itself cross MaxBytes again should land in the
+	// new, post-rotation file rather than re-triggering rotation.
+	if _, err := w.Write([]byte("ab")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "ab" {
+		t.Fatalf("current file contents = %q, want %q", got, "ab")
+	}
+}
+
+// TestFileWriterRotatesOnIntervalUnderConcurrentWrites exercises the
+// Hourly/Daily rotation path with concurrent writers, which previously read
+// w.opened (a plain time.Time) from Write's lock-free fast path while
+// rotate() wrote it under w.mu - an unsynchronized concurrent read/write
+// that -race flags. It backdates w.opened instead of waiting a real hour.
+func TestFileWriterRotatesOnIntervalUnderConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, FileOpts{Interval: Hourly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// See TestFileWriterRotatesOnMaxBytesAndRepointsSymlink: avoid the
+	// rotated-out backup colliding with the initial open's filename,
+	// which is second-granular.
+	time.Sleep(1100 * time.Millisecond)
+	atomic.StoreInt64(&w.opened, time.Now().Add(-2*time.Hour).UnixNano())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w.Write([]byte(fmt.Sprintf("line %d\n", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	matches, err := filepath.Glob(path + ".*.log*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) < 2 {
+		t.Fatalf("expected the interval-triggered rotation to leave a backup alongside the current file, got %v", matches)
+	}
+}
+
+func TestFileWriterGzipsRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, FileOpts{MaxBytes: 4, Gzip: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// See TestFileWriterRotatesOnMaxBytesAndRepointsSymlink: avoid the
+	// initial open and the triggered rotation landing in the same second.
+	time.Sleep(1100 * time.Millisecond)
+
+	if _, err := w.Write([]byte("1234")); err != nil {
+		t.Fatal(err)
+	}
+
+	var gz string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		matches, _ := filepath.Glob(path + ".*.log.gz")
+		if len(matches) == 1 {
+			gz = matches[0]
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if gz == "" {
+		t.Fatal("finalize did not produce a .gz file for the rotated-out log")
+	}
+
+	f, err := os.Open(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "1234" {
+		t.Fatalf("gzipped contents = %q, want %q", got, "1234")
+	}
+
+	// Only the new current file should remain uncompressed; the rotated-out
+	// one should have been removed once gzipped.
+	matches, _ := filepath.Glob(path + ".*.log")
+	if len(matches) != 1 {
+		t.Fatalf("expected only the current file left uncompressed, found: %v", matches)
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(matches[0]) != target {
+		t.Fatalf("leftover uncompressed file %v is not the current file %q", matches, target)
+	}
+}
+
+func TestFileWriterPrunesBackupsByMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w := &fileWriter{path: path, opts: FileOpts{MaxBackups: 1}}
+
+	now := time.Now()
+	for i, name := range []string{"app.log.20260101-000000.log", "app.log.20260102-000000.log", "app.log.20260103-000000.log"} {
+		full := filepath.Join(dir, name)
+		if err := os.WriteFile(full, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(full, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w.pruneBackups()
+
+	matches, err := filepath.Glob(path + ".*.log*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected pruning down to 1 backup, got %d: %v", len(matches), matches)
+	}
+	if filepath.Base(matches[0]) != "app.log.20260103-000000.log" {
+		t.Fatalf("expected the newest backup to survive, kept %v", matches)
+	}
+}
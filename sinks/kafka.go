@@ -0,0 +1,76 @@
+package sinks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaOpts configures NewKafkaWriter.
+type KafkaOpts struct {
+	// BatchTimeout bounds how long the underlying producer waits before
+	// flushing a partial batch. Zero uses kafka-go's default.
+	BatchTimeout time.Duration
+	// Async, when true, does not wait for broker acknowledgement before
+	// Write returns, trading durability for throughput on the hot path.
+	Async bool
+}
+
+type kafkaWriter struct {
+	mu      sync.Mutex
+	brokers []string
+	topic   string
+	opts    KafkaOpts
+	w       *kafka.Writer
+}
+
+// NewKafkaWriter returns an io.WriteCloser that produces each Write as a
+// single message on topic, load-balanced across brokers.
+func NewKafkaWriter(brokers []string, topic string, opts KafkaOpts) *kafkaWriter {
+	k := &kafkaWriter{brokers: brokers, topic: topic, opts: opts}
+	k.w = k.newWriter()
+	return k
+}
+
+func (k *kafkaWriter) newWriter() *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(k.brokers...),
+		Topic:        k.topic,
+		Balancer:     &kafka.LeastBytes{},
+		Async:        k.opts.Async,
+		RequiredAcks: kafka.RequireOne,
+		BatchTimeout: k.opts.BatchTimeout,
+	}
+}
+
+func (k *kafkaWriter) Write(p []byte) (int, error) {
+	k.mu.Lock()
+	w := k.w
+	k.mu.Unlock()
+
+	msg := kafka.Message{Value: append([]byte(nil), p...)}
+	if err := w.WriteMessages(context.Background(), msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush forces out any batch still held by an Async writer. kafka-go's
+// Writer has no flush-without-close call, so this closes the current
+// writer (which blocks until its pending writes complete) and swaps in a
+// fresh one so Write keeps working afterwards.
+func (k *kafkaWriter) Flush() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	err := k.w.Close()
+	k.w = k.newWriter()
+	return err
+}
+
+func (k *kafkaWriter) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.w.Close()
+}
@@ -0,0 +1,36 @@
+package sinks
+
+import "testing"
+
+// TestKafkaWriterWiresBatchTimeout checks NewKafkaWriter's underlying
+// kafka.Writer actually carries through KafkaOpts.BatchTimeout, since
+// there's no broker available here to exercise Write/Flush against.
+func TestKafkaWriterWiresBatchTimeout(t *testing.T) {
+	const want = 37
+
+	k := NewKafkaWriter([]string{"localhost:9092"}, "logs", KafkaOpts{
+		BatchTimeout: want,
+		Async:        true,
+	})
+
+	if got := k.w.BatchTimeout; got != want {
+		t.Fatalf("BatchTimeout = %v, want %v", got, want)
+	}
+	if !k.w.Async {
+		t.Fatal("expected Async to be wired through")
+	}
+}
+
+func TestKafkaWriterFlushReplacesWriter(t *testing.T) {
+	k := NewKafkaWriter([]string{"localhost:9092"}, "logs", KafkaOpts{})
+	orig := k.w
+
+	// Flush will fail to contact a broker, but it must still swap in a
+	// fresh writer so Write keeps working afterwards instead of reusing
+	// the now-closed one.
+	k.Flush()
+
+	if k.w == orig {
+		t.Fatal("expected Flush to replace the closed writer")
+	}
+}
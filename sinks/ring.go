@@ -0,0 +1,41 @@
+package sinks
+
+// ringBuffer holds the most recent bytes written to it, discarding the
+// oldest bytes once full. It backs connWriter's disconnect buffer.
+type ringBuffer struct {
+	buf   []byte
+	start int
+	len   int
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	if size <= 0 {
+		size = 64 * 1024
+	}
+	return &ringBuffer{buf: make([]byte, size)}
+}
+
+func (r *ringBuffer) Write(p []byte) {
+	size := len(r.buf)
+	for _, b := range p {
+		r.buf[(r.start+r.len)%size] = b
+		if r.len < size {
+			r.len++
+		} else {
+			r.start = (r.start + 1) % size
+		}
+	}
+}
+
+func (r *ringBuffer) Bytes() []byte {
+	size := len(r.buf)
+	out := make([]byte, r.len)
+	for i := 0; i < r.len; i++ {
+		out[i] = r.buf[(r.start+i)%size]
+	}
+	return out
+}
+
+func (r *ringBuffer) Reset() {
+	r.start, r.len = 0, 0
+}
@@ -0,0 +1,22 @@
+package sinks
+
+import "testing"
+
+func TestRingBufferWrapsAndDiscardsOldest(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]byte("ab"))
+	r.Write([]byte("cde"))
+
+	got := string(r.Bytes())
+	if want := "bcde"; got != want {
+		t.Fatalf("Bytes() = %q, want %q", got, want)
+	}
+
+	r.Reset()
+	if r.len != 0 {
+		t.Fatalf("len after Reset = %d, want 0", r.len)
+	}
+	if len(r.Bytes()) != 0 {
+		t.Fatalf("Bytes() after Reset = %q, want empty", r.Bytes())
+	}
+}
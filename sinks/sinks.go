@@ -0,0 +1,23 @@
+// Package sinks provides io.Writer implementations meant to be passed into
+// logz.Init or combined with logz.MultiWriter: syslog, TCP/UDP, and Kafka.
+package sinks
+
+import "time"
+
+// Backoff computes the delay before the n-th (0-based) reconnect attempt.
+type Backoff func(attempt int) time.Duration
+
+// DefaultBackoff doubles from 100ms up to a 30s ceiling.
+func DefaultBackoff(attempt int) time.Duration {
+	d := 100 * time.Millisecond << uint(attempt)
+	if d <= 0 || d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
+
+// Flusher is implemented by sinks that buffer writes and can be asked to
+// push them out on demand, e.g. before a graceful shutdown.
+type Flusher interface {
+	Flush() error
+}
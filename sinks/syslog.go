@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package sinks
+
+import "log/syslog"
+
+// Priority mirrors syslog.Priority so callers don't need to import
+// log/syslog directly just to call NewSyslogWriter.
+type Priority = syslog.Priority
+
+// NewSyslogWriter dials the local or remote syslog daemon and returns a
+// WriteCloser suitable for logz.Init or logz.MultiWriter. network and addr
+// are passed straight to syslog.Dial; an empty network dials the local
+// syslog daemon.
+func NewSyslogWriter(network, addr, tag string, priority Priority) (*syslog.Writer, error) {
+	return syslog.Dial(network, addr, priority, tag)
+}
@@ -0,0 +1,135 @@
+package logz
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is a verbosity level for V, independent of the severity-based LogLevel.
+type Level int32
+
+// Verbose is returned by V and no-ops its methods when the requested level
+// is not enabled, so callers avoid paying for fmt.Sprintf on the cold path.
+type Verbose bool
+
+var verbosity int32
+
+// SetVerbosity sets the global verbosity threshold used by V when no
+// -vmodule pattern matches the caller.
+func SetVerbosity(level Level) {
+	atomic.StoreInt32(&verbosity, int32(level))
+}
+
+type vmodulePattern struct {
+	pattern string
+	level   Level
+}
+
+// vmoduleEntry is what's cached per call site. matched is false when no
+// -vmodule pattern covers the site, meaning it falls through to the global
+// verbosity threshold; that threshold is re-read on every call instead of
+// being baked into the cache, so SetVerbosity takes effect immediately for
+// any site not also pinned by a vmodule pattern.
+type vmoduleEntry struct {
+	level   Level
+	matched bool
+}
+
+var (
+	vmoduleMu       sync.Mutex
+	vmodulePatterns []vmodulePattern
+	vmoduleCache    sync.Map // runtime PC -> vmoduleEntry
+)
+
+// SetVModule parses a glog/klog style -vmodule spec, e.g. "file=2,pkg/*=3",
+// enabling deep verbosity for matching call sites without raising it
+// everywhere. Patterns without a "/" match the caller's file name (without
+// extension); patterns containing "/" are matched against the full path.
+func SetVModule(spec string) error {
+	var patterns []vmodulePattern
+	for _, part := range strings.Split(spec, ",") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("logz: invalid vmodule entry %q", part)
+		}
+		level, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return fmt.Errorf("logz: invalid vmodule level in %q: %w", part, err)
+		}
+		patterns = append(patterns, vmodulePattern{pattern: kv[0], level: Level(level)})
+	}
+
+	vmoduleMu.Lock()
+	vmodulePatterns = patterns
+	vmoduleMu.Unlock()
+	vmoduleCache.Range(func(key, _ interface{}) bool {
+		vmoduleCache.Delete(key)
+		return true
+	})
+	return nil
+}
+
+// V reports whether verbose logging at the given level is enabled for the
+// caller's call site, honoring any -vmodule override. Whether a vmodule
+// pattern matches is cached per program counter, so repeated calls from the
+// same call site skip the pattern scan after the first; the global
+// verbosity threshold itself is never cached, so SetVerbosity is always
+// honored immediately.
+func V(level Level) Verbose {
+	pc, file, _, ok := runtime.Caller(1)
+	if !ok {
+		return Verbose(level <= Level(atomic.LoadInt32(&verbosity)))
+	}
+
+	if cached, ok := vmoduleCache.Load(pc); ok {
+		entry := cached.(vmoduleEntry)
+		if entry.matched {
+			return Verbose(level <= entry.level)
+		}
+		return Verbose(level <= Level(atomic.LoadInt32(&verbosity)))
+	}
+
+	vmoduleMu.Lock()
+	patterns := vmodulePatterns
+	vmoduleMu.Unlock()
+	for _, p := range patterns {
+		if matchVModule(p.pattern, file) {
+			vmoduleCache.Store(pc, vmoduleEntry{level: p.level, matched: true})
+			return Verbose(level <= p.level)
+		}
+	}
+
+	vmoduleCache.Store(pc, vmoduleEntry{matched: false})
+	return Verbose(level <= Level(atomic.LoadInt32(&verbosity)))
+}
+
+func matchVModule(pattern, file string) bool {
+	if strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, file)
+		return ok
+	}
+	base := filepath.Base(file)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	ok, _ := filepath.Match(pattern, base)
+	return ok
+}
+
+func (v Verbose) Info(args ...interface{}) {
+	if bool(v) && initialized {
+		std.log(LogLevelInfo, fmt.Sprint(args...))
+	}
+}
+
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if bool(v) && initialized {
+		std.log(LogLevelInfo, fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,32 @@
+package logz
+
+import "testing"
+
+// checkV wraps V(level) so every call in TestVFollowsSetVerbosityAfterCaching
+// shares one call site (and therefore one vmoduleCache entry) — V keys its
+// cache by the caller's program counter, which differs per call expression.
+// noinline keeps the compiler from collapsing that into distinct call sites.
+//
+//go:noinline
+func checkV(level Level) bool {
+	return bool(V(level))
+}
+
+// TestVFollowsSetVerbosityAfterCaching reproduces a bug where a call site
+// not covered by any -vmodule pattern cached its first resolved threshold
+// forever, so a later SetVerbosity never took effect there.
+func TestVFollowsSetVerbosityAfterCaching(t *testing.T) {
+	SetVerbosity(0)
+	if err := SetVModule(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if checkV(2) {
+		t.Fatal("expected V(2) to be disabled at verbosity 0")
+	}
+
+	SetVerbosity(5)
+	if !checkV(2) {
+		t.Fatal("expected V(2) to become enabled after SetVerbosity(5)")
+	}
+}